@@ -0,0 +1,210 @@
+package action
+
+import (
+	"encoding/json"
+
+	"bosh-google-cpi/google/instance"
+)
+
+// createVMCloudProperties mirrors the `cloud_properties` block of a create_vm CPI
+// request. It is unmarshalled from the request's raw JSON and converted into an
+// instance.Properties before being handed to GoogleInstanceService.Create.
+type createVMCloudProperties struct {
+	Zone              string            `json:"zone"`
+	MachineType       string            `json:"machine_type"`
+	RootDiskSizeGb    int               `json:"root_disk_size_gb"`
+	RootDiskType      string            `json:"root_disk_type"`
+	EphemeralDiskType string            `json:"ephemeral_disk_type"`
+	ServiceAccount    string            `json:"service_account"`
+	ServiceScopes     []string          `json:"service_scopes"`
+	Tags              []string          `json:"tags"`
+	Labels            map[string]string `json:"labels"`
+	TargetPool        string            `json:"target_pool"`
+	AutomaticRestart  bool              `json:"automatic_restart"`
+	OnHostMaintenance string            `json:"on_host_maintenance"`
+	Preemptible       bool              `json:"preemptible"`
+	NodeGroup         string            `json:"node_group"`
+
+	ProvisioningModel         string `json:"provisioning_model"`
+	InstanceTerminationAction string `json:"instance_termination_action"`
+	MaxRunDurationSeconds     int64  `json:"max_run_duration_seconds"`
+
+	// NetworkInterfaces is an alternative to the legacy single-network layout, letting a
+	// VM be created with more than one NIC (see instance.NetworkInterface).
+	NetworkInterfaces []networkInterfaceCloudProperties `json:"network_interfaces"`
+
+	ShieldedVM     *shieldedVMCloudProperties     `json:"shielded_vm"`
+	ConfidentialVM *confidentialVMCloudProperties `json:"confidential_vm"`
+
+	// DiskEncryption configures CMEK/CSEK for this VM's boot and ephemeral disks; see
+	// diskEncryptionCloudProperties.
+	DiskEncryption *diskEncryptionCloudProperties `json:"disk_encryption"`
+
+	SourceInstanceTemplate string                              `json:"source_instance_template"`
+	InstanceGroupManager   *instanceGroupManagerCloudProperties `json:"instance_group_manager"`
+
+	Metadata         map[string]string `json:"metadata"`
+	StartupScript    string            `json:"startup_script"`
+	StartupScriptURL string            `json:"startup_script_url"`
+}
+
+type instanceGroupManagerCloudProperties struct {
+	Name   string `json:"name"`
+	Zone   string `json:"zone"`
+	Region string `json:"region"`
+}
+
+type diskEncryptionCloudProperties struct {
+	KMSKeyName      string `json:"kms_key_name"`
+	RawKey          string `json:"raw_key"`
+	RSAEncryptedKey string `json:"rsa_encrypted_key"`
+}
+
+type shieldedVMCloudProperties struct {
+	EnableSecureBoot          bool `json:"enable_secure_boot"`
+	EnableVtpm                bool `json:"enable_vtpm"`
+	EnableIntegrityMonitoring bool `json:"enable_integrity_monitoring"`
+}
+
+type confidentialVMCloudProperties struct {
+	ConfidentialInstanceType string `json:"confidential_instance_type"`
+}
+
+type networkInterfaceCloudProperties struct {
+	Network       string                        `json:"network"`
+	ProjectID     string                        `json:"project_id"`
+	Subnetwork    string                        `json:"subnetwork"`
+	NetworkIP     string                        `json:"network_ip"`
+	ExternalIP    string                        `json:"external_ip"`
+	AliasIPRanges []aliasIPRangeCloudProperties `json:"alias_ip_ranges"`
+}
+
+type aliasIPRangeCloudProperties struct {
+	IPCIDRRange         string `json:"ip_cidr_range"`
+	SubnetworkRangeName string `json:"subnetwork_range_name"`
+}
+
+// ParseVMCloudProperties unmarshals a create_vm call's raw cloud_properties into an
+// instance.Properties, ready to be passed to GoogleInstanceService.Create.
+func ParseVMCloudProperties(raw json.RawMessage) (*instance.Properties, error) {
+	var cloudProps createVMCloudProperties
+	if err := json.Unmarshal(raw, &cloudProps); err != nil {
+		return nil, err
+	}
+
+	return &instance.Properties{
+		Zone:              cloudProps.Zone,
+		MachineType:       cloudProps.MachineType,
+		RootDiskSizeGb:    cloudProps.RootDiskSizeGb,
+		RootDiskType:      cloudProps.RootDiskType,
+		EphemeralDiskType: cloudProps.EphemeralDiskType,
+		ServiceAccount:    cloudProps.ServiceAccount,
+		ServiceScopes:     instance.ServiceScopes(cloudProps.ServiceScopes),
+		Tags:              instance.Tags(cloudProps.Tags),
+		Labels:            cloudProps.Labels,
+		TargetPool:        cloudProps.TargetPool,
+		AutomaticRestart:  cloudProps.AutomaticRestart,
+		OnHostMaintenance: cloudProps.OnHostMaintenance,
+		Preemptible:       cloudProps.Preemptible,
+		NodeGroup:         cloudProps.NodeGroup,
+
+		ProvisioningModel:         cloudProps.ProvisioningModel,
+		InstanceTerminationAction: cloudProps.InstanceTerminationAction,
+		MaxRunDurationSeconds:     cloudProps.MaxRunDurationSeconds,
+
+		NetworkInterfaces: toInstanceNetworkInterfaces(cloudProps.NetworkInterfaces),
+		ShieldedVM:        toInstanceShieldedVM(cloudProps.ShieldedVM),
+		ConfidentialVM:    toInstanceConfidentialVM(cloudProps.ConfidentialVM),
+		DiskEncryption:    toInstanceDiskEncryption(cloudProps.DiskEncryption),
+
+		SourceInstanceTemplate: cloudProps.SourceInstanceTemplate,
+		InstanceGroupManager:   toInstanceGroupManager(cloudProps.InstanceGroupManager),
+
+		Metadata:         cloudProps.Metadata,
+		StartupScript:    cloudProps.StartupScript,
+		StartupScriptURL: cloudProps.StartupScriptURL,
+	}, nil
+}
+
+func toInstanceGroupManager(in *instanceGroupManagerCloudProperties) instance.InstanceGroupManager {
+	if in == nil {
+		return instance.InstanceGroupManager{}
+	}
+
+	return instance.InstanceGroupManager{
+		Name:   in.Name,
+		Zone:   in.Zone,
+		Region: in.Region,
+	}
+}
+
+func toInstanceDiskEncryption(in *diskEncryptionCloudProperties) instance.DiskEncryption {
+	if in == nil {
+		return instance.DiskEncryption{}
+	}
+
+	return instance.DiskEncryption{
+		KMSKeyName:      in.KMSKeyName,
+		RawKey:          in.RawKey,
+		RSAEncryptedKey: in.RSAEncryptedKey,
+	}
+}
+
+func toInstanceShieldedVM(in *shieldedVMCloudProperties) instance.ShieldedVM {
+	if in == nil {
+		return instance.ShieldedVM{}
+	}
+
+	return instance.ShieldedVM{
+		EnableSecureBoot:          in.EnableSecureBoot,
+		EnableVtpm:                in.EnableVtpm,
+		EnableIntegrityMonitoring: in.EnableIntegrityMonitoring,
+	}
+}
+
+func toInstanceConfidentialVM(in *confidentialVMCloudProperties) instance.ConfidentialVM {
+	if in == nil {
+		return instance.ConfidentialVM{}
+	}
+
+	return instance.ConfidentialVM{
+		Enabled:                  true,
+		ConfidentialInstanceType: in.ConfidentialInstanceType,
+	}
+}
+
+func toInstanceNetworkInterfaces(in []networkInterfaceCloudProperties) []instance.NetworkInterface {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]instance.NetworkInterface, len(in))
+	for index, iface := range in {
+		out[index] = instance.NetworkInterface{
+			Network:       iface.Network,
+			ProjectID:     iface.ProjectID,
+			Subnetwork:    iface.Subnetwork,
+			NetworkIP:     iface.NetworkIP,
+			ExternalIP:    iface.ExternalIP,
+			AliasIPRanges: toInstanceAliasIPRanges(iface.AliasIPRanges),
+		}
+	}
+
+	return out
+}
+
+func toInstanceAliasIPRanges(in []aliasIPRangeCloudProperties) []instance.AliasIPRange {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]instance.AliasIPRange, len(in))
+	for index, r := range in {
+		out[index] = instance.AliasIPRange{
+			IPCIDRRange:         r.IPCIDRRange,
+			SubnetworkRangeName: r.SubnetworkRangeName,
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,57 @@
+package instance
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// DiskEncryption configures a customer-managed (or customer-supplied) encryption key
+// for a disk. Exactly one of KMSKeyName or RawKey is expected to be set; RSAEncryptedKey
+// may accompany RawKey when the key was wrapped for transmission.
+type DiskEncryption struct {
+	KMSKeyName      string
+	RawKey          string
+	RSAEncryptedKey string
+}
+
+func (d DiskEncryption) isEmpty() bool {
+	return d.KMSKeyName == "" && d.RawKey == "" && d.RSAEncryptedKey == ""
+}
+
+func (d DiskEncryption) toComputeDiskEncryptionKey() *compute.CustomerEncryptionKey {
+	if d.isEmpty() {
+		return nil
+	}
+
+	return &compute.CustomerEncryptionKey{
+		KmsKeyName:      d.KMSKeyName,
+		RawKey:          d.RawKey,
+		RsaEncryptedKey: d.RSAEncryptedKey,
+	}
+}
+
+// validateDiskEncryptionRegion errors out when a CMEK's KMS key lives in a different
+// region than the instance's zone, rather than letting the Insert call fail later with a
+// less specific message. KMS key resource URIs look like:
+// projects/P/locations/REGION/keyRings/R/cryptoKeys/K.
+func validateDiskEncryptionRegion(d DiskEncryption, zoneRegion string) error {
+	if d.KMSKeyName == "" {
+		return nil
+	}
+
+	parts := strings.Split(d.KMSKeyName, "/")
+	for index, part := range parts {
+		if part == "locations" && index+1 < len(parts) {
+			kmsRegion := parts[index+1]
+			if kmsRegion != "global" && kmsRegion != zoneRegion {
+				return bosherr.Errorf("KMS key region '%s' does not match instance region '%s'", kmsRegion, zoneRegion)
+			}
+			return nil
+		}
+	}
+
+	return bosherr.Errorf("Could not determine region from KMS key name '%s'", d.KMSKeyName)
+}
@@ -0,0 +1,57 @@
+package instance
+
+import (
+	"testing"
+)
+
+func TestValidateDiskEncryptionRegion(t *testing.T) {
+	cases := []struct {
+		name       string
+		kmsKeyName string
+		zoneRegion string
+		expectErr  bool
+	}{
+		{
+			name:       "no KMS key set",
+			kmsKeyName: "",
+			zoneRegion: "us-central1",
+			expectErr:  false,
+		},
+		{
+			name:       "KMS key region matches instance region",
+			kmsKeyName: "projects/p/locations/us-central1/keyRings/r/cryptoKeys/k",
+			zoneRegion: "us-central1",
+			expectErr:  false,
+		},
+		{
+			name:       "KMS key region is global",
+			kmsKeyName: "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+			zoneRegion: "us-central1",
+			expectErr:  false,
+		},
+		{
+			name:       "KMS key region does not match instance region",
+			kmsKeyName: "projects/p/locations/europe-west1/keyRings/r/cryptoKeys/k",
+			zoneRegion: "us-central1",
+			expectErr:  true,
+		},
+		{
+			name:       "KMS key name has no discernible region",
+			kmsKeyName: "not-a-valid-kms-key-name",
+			zoneRegion: "us-central1",
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateDiskEncryptionRegion(DiskEncryption{KMSKeyName: tc.kmsKeyName}, tc.zoneRegion)
+			if tc.expectErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package instance
+
+import (
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+
+	"bosh-google-cpi/google/backendservice"
+	"bosh-google-cpi/google/disktype"
+	"bosh-google-cpi/google/network"
+	"bosh-google-cpi/google/operation"
+	subnet "bosh-google-cpi/google/subnetwork"
+	"bosh-google-cpi/google/targetpool"
+	"bosh-google-cpi/logger"
+
+	"google.golang.org/api/compute/v1"
+)
+
+const googleInstanceServiceLogTag = "GoogleInstanceService"
+const googleInstanceNamePrefix = "bosh"
+const googleInstanceDescription = "bosh"
+
+// GoogleInstanceService creates, finds and destroys Google Compute Engine instances on
+// behalf of the CPI's create_vm/delete_vm/has_vm actions.
+type GoogleInstanceService struct {
+	project               string
+	computeService        *compute.Service
+	uuidGen               boshuuid.Generator
+	networkService        network.Service
+	subnetworkService     subnet.Service
+	diskTypeService       disktype.Service
+	targetPoolService     targetpool.Service
+	backendServiceService backendservice.Service
+	operationService      operation.Service
+	logger                logger.Logger
+
+	// defaultDiskEncryption is the CMEK (or CSEK) applied to every disk this service
+	// creates unless a VM's own Properties.DiskEncryption overrides it, letting an
+	// operator enforce encryption for every VM in a deployment from the CPI config.
+	defaultDiskEncryption DiskEncryption
+}
+
+func NewGoogleInstanceService(
+	project string,
+	computeService *compute.Service,
+	uuidGen boshuuid.Generator,
+	networkService network.Service,
+	subnetworkService subnet.Service,
+	diskTypeService disktype.Service,
+	targetPoolService targetpool.Service,
+	backendServiceService backendservice.Service,
+	operationService operation.Service,
+	defaultDiskEncryption DiskEncryption,
+	logger logger.Logger,
+) GoogleInstanceService {
+	return GoogleInstanceService{
+		project:               project,
+		computeService:        computeService,
+		uuidGen:               uuidGen,
+		networkService:        networkService,
+		subnetworkService:     subnetworkService,
+		diskTypeService:       diskTypeService,
+		targetPoolService:     targetPoolService,
+		backendServiceService: backendServiceService,
+		operationService:      operationService,
+		defaultDiskEncryption: defaultDiskEncryption,
+		logger:                logger,
+	}
+}
@@ -20,6 +20,24 @@ const defaultRootDiskSizeGb = 10
 const userDataKey = "user_data"
 const nodeGroupNodeAffinityKey = "compute.googleapis.com/node-group-name"
 
+// startupScriptKey and startupScriptURLKey follow GCE's own startup-script metadata
+// convention, so an operator's existing GCS-hosted startup scripts work unchanged.
+const startupScriptKey = "startup-script"
+const startupScriptURLKey = "startup-script-url"
+
+// maxNetworkInterfaces mirrors GCE's current per-instance NIC limit.
+const maxNetworkInterfaces = 8
+
+const provisioningModelSpot = "SPOT"
+
+// confidentialVMMachineSeries lists the machine series that currently support
+// Confidential VM (AMD SEV/SEV-SNP).
+var confidentialVMMachineSeries = map[string]bool{
+	"n2d": true,
+	"c2d": true,
+	"c3d": true,
+}
+
 func (i GoogleInstanceService) Create(vmProps *Properties, networks Networks, registryEndpoint string) (string, error) {
 	uuidStr, err := i.uuidGen.Generate()
 	if err != nil {
@@ -31,17 +49,50 @@ func (i GoogleInstanceService) Create(vmProps *Properties, networks Networks, re
 		instanceName = fmt.Sprintf("%s-%s", googleInstanceNamePrefix, uuidStr)
 	}
 	canIPForward := networks.CanIPForward()
-	diskParams := i.createDiskParams(vmProps.Stemcell, vmProps.RootDiskSizeGb, vmProps.RootDiskType)
-	metadataParams, err := i.createMatadataParams(instanceName, registryEndpoint, networks)
+
+	diskEncryption := vmProps.DiskEncryption
+	if diskEncryption.isEmpty() {
+		diskEncryption = i.defaultDiskEncryption
+	}
+	if err := validateDiskEncryptionRegion(diskEncryption, util.RegionFromZone(vmProps.Zone)); err != nil {
+		return "", err
+	}
+
+	diskParams := i.createDiskParams(vmProps.Stemcell, vmProps.RootDiskSizeGb, vmProps.RootDiskType, diskEncryption)
+	metadataParams, err := i.createMatadataParams(instanceName, registryEndpoint, networks, vmProps)
+	if err != nil {
+		return "", err
+	}
+	networkInterfacesParams, err := i.createNetworkInterfacesParams(vmProps, networks, vmProps.Zone)
 	if err != nil {
 		return "", err
 	}
-	networkInterfacesParams, err := i.createNetworkInterfacesParams(networks, vmProps.Zone)
+	onHostMaintenance := vmProps.OnHostMaintenance
+	if vmProps.ConfidentialVM.Enabled {
+		series := parseMachineTypeSeries(vmProps.MachineType)
+		if !confidentialVMMachineSeries[series] {
+			return "", bosherr.Errorf("Confidential VM is not supported on machine series '%s'", series)
+		}
+		// Live migration is incompatible with Confidential VM, so force the instance to
+		// be terminated rather than migrated regardless of what was configured.
+		onHostMaintenance = "TERMINATE"
+	}
+
+	schedulingParams, err := i.createSchedulingParams(
+		vmProps.AutomaticRestart,
+		onHostMaintenance,
+		vmProps.Preemptible,
+		vmProps.NodeGroup,
+		vmProps.ProvisioningModel,
+		vmProps.InstanceTerminationAction,
+		vmProps.MaxRunDurationSeconds,
+	)
 	if err != nil {
 		return "", err
 	}
-	schedulingParams := i.createSchedulingParams(vmProps.AutomaticRestart, vmProps.OnHostMaintenance, vmProps.Preemptible, vmProps.NodeGroup)
 	serviceAccountsParams := i.createServiceAccountsParams(vmProps)
+	shieldedInstanceConfig := i.createShieldedInstanceConfigParams(vmProps.ShieldedVM)
+	confidentialInstanceConfig := i.createConfidentialInstanceConfigParams(vmProps.ConfidentialVM)
 
 	// Handle tags
 	allTags := append(networks.Tags(), vmProps.Tags...)
@@ -61,7 +112,7 @@ func (i GoogleInstanceService) Create(vmProps *Properties, networks Networks, re
 		// of either n1-standard-1, custom-4-5120, or a2-highgpu-1g
 		machineTypeName := vmProps.MachineType[strings.LastIndex(vmProps.MachineType, "/")+1:]
 		machineTypeComponents := strings.Split(machineTypeName, "-")
-		machineTypeSeries := machineTypeComponents[0] // e.g. n1, custom, a2
+		machineTypeSeries := parseMachineTypeSeries(vmProps.MachineType) // e.g. n1, custom, a2
 
 		numberOfCPUs := 0
 
@@ -115,7 +166,7 @@ func (i GoogleInstanceService) Create(vmProps *Properties, networks Networks, re
 			}
 		}
 		for j := 0; j < numberOfLocalSSDs; j++ {
-			ssdDisk, err = i.createLocalSSDParams(vmProps.Zone, j+1)
+			ssdDisk, err = i.createLocalSSDParams(vmProps.Zone, j+1, diskEncryption)
 			if err != nil {
 				return "", err
 			}
@@ -125,23 +176,51 @@ func (i GoogleInstanceService) Create(vmProps *Properties, networks Networks, re
 	}
 
 	vm := &compute.Instance{
-		Name:              instanceName,
-		Description:       googleInstanceDescription,
-		CanIpForward:      canIPForward,
-		Disks:             diskParams,
-		MachineType:       vmProps.MachineType,
-		Metadata:          metadataParams,
-		NetworkInterfaces: networkInterfacesParams,
-		Scheduling:        schedulingParams,
-		ServiceAccounts:   serviceAccountsParams,
-		Tags:              &tags,
-		Labels:            vmProps.Labels,
-		GuestAccelerators: acceleratorParams,
-		MinCpuPlatform:    "",
+		Name:                       instanceName,
+		Description:                googleInstanceDescription,
+		CanIpForward:               canIPForward,
+		Disks:                      diskParams,
+		MachineType:                vmProps.MachineType,
+		Metadata:                   metadataParams,
+		NetworkInterfaces:          networkInterfacesParams,
+		Scheduling:                 schedulingParams,
+		ServiceAccounts:            serviceAccountsParams,
+		Tags:                       &tags,
+		Labels:                     vmProps.Labels,
+		GuestAccelerators:          acceleratorParams,
+		MinCpuPlatform:             "",
+		ShieldedInstanceConfig:     shieldedInstanceConfig,
+		ConfidentialInstanceConfig: confidentialInstanceConfig,
+	}
+
+	// When an InstanceGroupManager is set, the MIG itself provisions the instance under
+	// this name; a standalone Instances.Insert with the same name would always fail with
+	// a name conflict, and the MIG already takes care of any target pool / backend
+	// service membership configured on it.
+	if vmProps.InstanceGroupManager.Name != "" {
+		if err := i.addToInstanceGroupManager(vm.Name, vmProps.InstanceGroupManager, vm.Metadata); err != nil {
+			i.logger.Debug(googleInstanceServiceLogTag, "Failed to create Google Instance via Instance Group Manager: %v", err)
+			return "", api.NewVMCreationFailedError(err.Error(), true)
+		}
+
+		return vm.Name, nil
+	}
+
+	insertCall := i.computeService.Instances.Insert(i.project, util.ResourceSplitter(vmProps.Zone), vm)
+	if vmProps.SourceInstanceTemplate != "" {
+		// Only the fields that differ per-VM are set; everything else is inherited from
+		// the template.
+		templateOverrides := &compute.Instance{
+			Name:              vm.Name,
+			Metadata:          vm.Metadata,
+			NetworkInterfaces: vm.NetworkInterfaces,
+		}
+		insertCall = i.computeService.Instances.Insert(i.project, util.ResourceSplitter(vmProps.Zone), templateOverrides).
+			SourceInstanceTemplate(vmProps.SourceInstanceTemplate)
 	}
 
 	i.logger.Debug(googleInstanceServiceLogTag, "Creating Google Instance with params: %v", vm)
-	operation, err := i.computeService.Instances.Insert(i.project, util.ResourceSplitter(vmProps.Zone), vm).Do()
+	operation, err := insertCall.Do()
 	if err != nil {
 		i.logger.Debug(googleInstanceServiceLogTag, "Failed to create Google Instance: %v", err)
 		return "", api.NewVMCreationFailedError(err.Error(), true)
@@ -179,7 +258,7 @@ func (i GoogleInstanceService) CleanUp(id string) {
 
 }
 
-func (i GoogleInstanceService) createDiskParams(stemcell string, diskSize int, diskType string) []*compute.AttachedDisk {
+func (i GoogleInstanceService) createDiskParams(stemcell string, diskSize int, diskType string, diskEncryption DiskEncryption) []*compute.AttachedDisk {
 	var disks []*compute.AttachedDisk
 
 	if diskSize == 0 {
@@ -193,15 +272,16 @@ func (i GoogleInstanceService) createDiskParams(stemcell string, diskSize int, d
 			DiskType:    diskType,
 			SourceImage: stemcell,
 		},
-		Mode: "READ_WRITE",
-		Type: "PERSISTENT",
+		DiskEncryptionKey: diskEncryption.toComputeDiskEncryptionKey(),
+		Mode:              "READ_WRITE",
+		Type:              "PERSISTENT",
 	}
 	disks = append(disks, disk)
 
 	return disks
 }
 
-func (i GoogleInstanceService) createLocalSSDParams(zone string, index int) (*compute.AttachedDisk, error) {
+func (i GoogleInstanceService) createLocalSSDParams(zone string, index int, diskEncryption DiskEncryption) (*compute.AttachedDisk, error) {
 	diskType, b, e := i.diskTypeService.Find("local-ssd", zone)
 	if e != nil {
 		return nil, e
@@ -216,9 +296,10 @@ func (i GoogleInstanceService) createLocalSSDParams(zone string, index int) (*co
 		InitializeParams: &compute.AttachedDiskInitializeParams{
 			DiskType: diskType.SelfLink,
 		},
-		Interface: "NVME",
-		Index:     int64(index),
-		Type:      "SCRATCH",
+		DiskEncryptionKey: diskEncryption.toComputeDiskEncryptionKey(),
+		Interface:         "NVME",
+		Index:             int64(index),
+		Type:              "SCRATCH",
 	}
 
 	return disk, nil
@@ -238,7 +319,33 @@ func (i GoogleInstanceService) createAcceleratorParams(accelerators []Accelerato
 	return accs
 }
 
-func (i GoogleInstanceService) createMatadataParams(name string, regEndpoint string, networks Networks) (*compute.Metadata, error) {
+// parseMachineTypeSeries extracts the machine series (e.g. n1, custom, a2, n2d) from a
+// MachineType resource URI of the form zones/zone/machineTypes/machine-type.
+func parseMachineTypeSeries(machineType string) string {
+	machineTypeName := machineType[strings.LastIndex(machineType, "/")+1:]
+	return strings.Split(machineTypeName, "-")[0]
+}
+
+func (i GoogleInstanceService) createShieldedInstanceConfigParams(shieldedVM ShieldedVM) *compute.ShieldedInstanceConfig {
+	return &compute.ShieldedInstanceConfig{
+		EnableSecureBoot:          shieldedVM.EnableSecureBoot,
+		EnableVtpm:                shieldedVM.EnableVtpm,
+		EnableIntegrityMonitoring: shieldedVM.EnableIntegrityMonitoring,
+	}
+}
+
+func (i GoogleInstanceService) createConfidentialInstanceConfigParams(confidentialVM ConfidentialVM) *compute.ConfidentialInstanceConfig {
+	if !confidentialVM.Enabled {
+		return nil
+	}
+
+	return &compute.ConfidentialInstanceConfig{
+		EnableConfidentialCompute: true,
+		ConfidentialInstanceType:  confidentialVM.ConfidentialInstanceType,
+	}
+}
+
+func (i GoogleInstanceService) createMatadataParams(name string, regEndpoint string, networks Networks, vmProps *Properties) (*compute.Metadata, error) {
 	serverName := GoogleUserDataServerName{Name: name}
 	registryEndpoint := GoogleUserDataRegistryEndpoint{Endpoint: regEndpoint}
 	userData := GoogleUserData{Server: serverName, Registry: registryEndpoint}
@@ -256,12 +363,35 @@ func (i GoogleInstanceService) createMatadataParams(name string, regEndpoint str
 	userDataValue := string(ud)
 	metadataItem := &compute.MetadataItems{Key: userDataKey, Value: &userDataValue}
 	metadataItems = append(metadataItems, metadataItem)
+
+	for key, value := range vmProps.Metadata {
+		if key == userDataKey || key == startupScriptKey || key == startupScriptURLKey {
+			return nil, bosherr.Errorf("metadata key '%s' is reserved and cannot be overridden", key)
+		}
+		value := value
+		metadataItems = append(metadataItems, &compute.MetadataItems{Key: key, Value: &value})
+	}
+
+	if vmProps.StartupScript != "" {
+		startupScript := vmProps.StartupScript
+		metadataItems = append(metadataItems, &compute.MetadataItems{Key: startupScriptKey, Value: &startupScript})
+	}
+
+	if vmProps.StartupScriptURL != "" {
+		startupScriptURL := vmProps.StartupScriptURL
+		metadataItems = append(metadataItems, &compute.MetadataItems{Key: startupScriptURLKey, Value: &startupScriptURL})
+	}
+
 	metadata := &compute.Metadata{Items: metadataItems}
 
 	return metadata, nil
 }
 
-func (i GoogleInstanceService) createNetworkInterfacesParams(networks Networks, zone string) ([]*compute.NetworkInterface, error) {
+func (i GoogleInstanceService) createNetworkInterfacesParams(vmProps *Properties, networks Networks, zone string) ([]*compute.NetworkInterface, error) {
+	if len(vmProps.NetworkInterfaces) > 0 {
+		return i.createMultiNetworkInterfacesParams(vmProps.NetworkInterfaces, networks, zone)
+	}
+
 	network, found, err := i.networkService.Find(networks.NetworkProjectID(), networks.NetworkName())
 	if err != nil {
 		return nil, err
@@ -308,14 +438,106 @@ func (i GoogleInstanceService) createNetworkInterfacesParams(networks Networks,
 	return networkInterfaces, nil
 }
 
+// createMultiNetworkInterfacesParams builds one *compute.NetworkInterface per entry in
+// ifaces, preserving order so that the first entry becomes the VM's primary NIC. Each
+// interface may carry its own access config and alias IP ranges, which the single-NIC
+// path above has no way to express.
+func (i GoogleInstanceService) createMultiNetworkInterfacesParams(ifaces []NetworkInterface, networks Networks, zone string) ([]*compute.NetworkInterface, error) {
+	if len(ifaces) > maxNetworkInterfaces {
+		return nil, bosherr.Errorf("a VM may have at most %d network interfaces, got %d", maxNetworkInterfaces, len(ifaces))
+	}
+
+	// Order is preserved so that ifaces[0] becomes the VM's primary NIC, matching the
+	// order the operator supplied in cloud_properties.
+	var networkInterfaces []*compute.NetworkInterface
+	for _, iface := range ifaces {
+		projectID := iface.ProjectID
+		if projectID == "" {
+			projectID = networks.NetworkProjectID()
+		}
+
+		network, found, err := i.networkService.Find(projectID, iface.Network)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, bosherr.WrapErrorf(err, "Network '%s' does not exist in project '%s'", iface.Network, projectID)
+		}
+
+		subnetworkLink := ""
+		if iface.Subnetwork != "" {
+			subnetwork, err := i.subnetworkService.Find(projectID, iface.Subnetwork, util.RegionFromZone(zone))
+			if err != nil {
+				if err == subnet.ErrSubnetNotFound {
+					return nil, bosherr.WrapErrorf(err, "Subnetwork '%s' does not exist in project '%s'", iface.Subnetwork, projectID)
+				}
+				return nil, err
+			}
+			subnetworkLink = subnetwork.SelfLink
+		}
+
+		var accessConfigs []*compute.AccessConfig
+		if iface.ExternalIP != "" {
+			accessConfigs = append(accessConfigs, &compute.AccessConfig{
+				Name:  "External NAT",
+				Type:  "ONE_TO_ONE_NAT",
+				NatIP: iface.ExternalIP,
+			})
+		}
+
+		var aliasIPRanges []*compute.AliasIpRange
+		for _, aliasRange := range iface.AliasIPRanges {
+			aliasIPRanges = append(aliasIPRanges, &compute.AliasIpRange{
+				IpCidrRange:         aliasRange.IPCIDRRange,
+				SubnetworkRangeName: aliasRange.SubnetworkRangeName,
+			})
+		}
+
+		networkInterfaces = append(networkInterfaces, &compute.NetworkInterface{
+			Network:       network.SelfLink,
+			Subnetwork:    subnetworkLink,
+			NetworkIP:     iface.NetworkIP,
+			AccessConfigs: accessConfigs,
+			AliasIpRanges: aliasIPRanges,
+		})
+	}
+
+	return networkInterfaces, nil
+}
+
 func (i GoogleInstanceService) createSchedulingParams(
 	automaticRestart bool,
 	onHostMaintenance string,
 	preemptible bool,
 	nodeGroup string,
-) *compute.Scheduling {
-	if preemptible {
-		return &compute.Scheduling{Preemptible: preemptible}
+	provisioningModel string,
+	instanceTerminationAction string,
+	maxRunDurationSeconds int64,
+) (*compute.Scheduling, error) {
+	// Preserve backward compatibility: a bare `preemptible: true`, with no explicit
+	// provisioning_model, is translated into the modern Spot equivalent.
+	if preemptible && provisioningModel == "" {
+		provisioningModel = provisioningModelSpot
+		if instanceTerminationAction == "" {
+			instanceTerminationAction = "DELETE"
+		}
+	}
+
+	if provisioningModel == provisioningModelSpot {
+		if automaticRestart {
+			return nil, bosherr.Error("automatic_restart is not supported in combination with a Spot VM")
+		}
+
+		scheduling := &compute.Scheduling{
+			Preemptible:               true,
+			ProvisioningModel:         provisioningModelSpot,
+			InstanceTerminationAction: instanceTerminationAction,
+		}
+		if maxRunDurationSeconds > 0 {
+			scheduling.MaxRunDuration = &compute.Duration{Seconds: maxRunDurationSeconds}
+		}
+
+		return scheduling, nil
 	}
 
 	scheduling := &compute.Scheduling{
@@ -336,7 +558,7 @@ func (i GoogleInstanceService) createSchedulingParams(
 		scheduling.OnHostMaintenance = "MIGRATE"
 	}
 
-	return scheduling
+	return scheduling, nil
 }
 
 func (i GoogleInstanceService) createServiceAccountsParams(vmProps *Properties) []*compute.ServiceAccount {
@@ -403,6 +625,61 @@ func (i GoogleInstanceService) addToBackendService(instanceSelfLink string, back
 	return nil
 }
 
+// addToInstanceGroupManager adds instanceName to an existing zonal or regional MIG as
+// an additional stateful instance, rather than having the MIG create and manage the
+// instance's identity itself - this is what lets a BOSH-managed VM get MIG
+// autohealing/rolling-update behaviour without BOSH losing ownership of it.
+func (i GoogleInstanceService) addToInstanceGroupManager(instanceName string, mig InstanceGroupManager, metadata *compute.Metadata) error {
+	if (mig.Zone == "") == (mig.Region == "") {
+		return bosherr.Errorf("Instance Group Manager '%s' must set exactly one of zone or region", mig.Name)
+	}
+
+	perInstanceConfig := &compute.PerInstanceConfig{
+		Name:           instanceName,
+		PreservedState: &compute.PreservedState{Metadata: toPreservedStateMetadata(metadata)},
+	}
+	request := &compute.InstanceGroupManagersCreateInstancesRequest{
+		Instances: []*compute.PerInstanceConfig{perInstanceConfig},
+	}
+
+	if mig.Region != "" {
+		operation, err := i.computeService.RegionInstanceGroupManagers.CreateInstances(i.project, mig.Region, mig.Name, &compute.RegionInstanceGroupManagersCreateInstancesRequest{
+			Instances: request.Instances,
+		}).Do()
+		if err != nil {
+			return err
+		}
+		_, err = i.operationService.Waiter(operation, "", mig.Region)
+		return err
+	}
+
+	operation, err := i.computeService.InstanceGroupManagers.CreateInstances(i.project, mig.Zone, mig.Name, request).Do()
+	if err != nil {
+		return err
+	}
+	_, err = i.operationService.Waiter(operation, mig.Zone, "")
+	return err
+}
+
+// toPreservedStateMetadata converts the instance's metadata items (BOSH's user_data plus
+// any operator-supplied metadata/startup-script) into the map form that MIG per-instance
+// configs preserve, so a MIG-provisioned VM still gets its own registry endpoint/server
+// name instead of whatever generic metadata the MIG's template carries.
+func toPreservedStateMetadata(metadata *compute.Metadata) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+
+	preserved := map[string]string{}
+	for _, item := range metadata.Items {
+		if item.Value != nil {
+			preserved[item.Key] = *item.Value
+		}
+	}
+
+	return preserved
+}
+
 func (i GoogleInstanceService) removeFromBackendService(instanceSelfLink string) error {
 	if err := i.backendServiceService.RemoveInstance(instanceSelfLink); err != nil {
 		return err
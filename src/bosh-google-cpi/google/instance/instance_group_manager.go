@@ -0,0 +1,12 @@
+package instance
+
+// InstanceGroupManager identifies an existing zonal or regional Managed Instance Group
+// (MIG) that a newly created VM should be added to as an additional stateful instance,
+// instead of legacy TargetPool/BackendService membership: when it is set, the VM's own
+// TargetPool and BackendService properties are not applied. Exactly one of Zone or
+// Region is expected to be set, matching whichever kind of MIG Name refers to.
+type InstanceGroupManager struct {
+	Name   string
+	Zone   string
+	Region string
+}
@@ -0,0 +1,45 @@
+package instance
+
+import (
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+)
+
+func TestAddToInstanceGroupManager_RejectsNeitherZoneNorRegion(t *testing.T) {
+	var service GoogleInstanceService
+
+	err := service.addToInstanceGroupManager("some-instance", InstanceGroupManager{Name: "mig"}, nil)
+	if err == nil {
+		t.Fatalf("expected an error when neither zone nor region is set, got nil")
+	}
+}
+
+func TestAddToInstanceGroupManager_RejectsBothZoneAndRegion(t *testing.T) {
+	var service GoogleInstanceService
+
+	err := service.addToInstanceGroupManager("some-instance", InstanceGroupManager{Name: "mig", Zone: "us-central1-a", Region: "us-central1"}, nil)
+	if err == nil {
+		t.Fatalf("expected an error when both zone and region are set, got nil")
+	}
+}
+
+func TestToPreservedStateMetadata(t *testing.T) {
+	userData := "some-user-data"
+	metadata := &compute.Metadata{
+		Items: []*compute.MetadataItems{
+			{Key: userDataKey, Value: &userData},
+		},
+	}
+
+	preserved := toPreservedStateMetadata(metadata)
+	if preserved[userDataKey] != userData {
+		t.Fatalf("expected preserved metadata to carry '%s', got %v", userDataKey, preserved)
+	}
+}
+
+func TestToPreservedStateMetadata_NilMetadata(t *testing.T) {
+	if preserved := toPreservedStateMetadata(nil); preserved != nil {
+		t.Fatalf("expected nil metadata to produce nil preserved state, got %v", preserved)
+	}
+}
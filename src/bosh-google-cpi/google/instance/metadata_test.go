@@ -0,0 +1,70 @@
+package instance
+
+import (
+	"testing"
+)
+
+func TestCreateMatadataParams_RejectsReservedUserDataKey(t *testing.T) {
+	var service GoogleInstanceService
+
+	vmProps := &Properties{
+		Metadata: map[string]string{userDataKey: "overridden"},
+	}
+
+	_, err := service.createMatadataParams("some-name", "", Networks{}, vmProps)
+	if err == nil {
+		t.Fatalf("expected an error when metadata overrides the reserved '%s' key, got nil", userDataKey)
+	}
+}
+
+func TestCreateMatadataParams_RejectsMetadataCollidingWithStartupScriptKeys(t *testing.T) {
+	cases := []string{startupScriptKey, startupScriptURLKey}
+
+	for _, key := range cases {
+		t.Run(key, func(t *testing.T) {
+			var service GoogleInstanceService
+
+			vmProps := &Properties{
+				Metadata:      map[string]string{key: "overridden"},
+				StartupScript: "#!/bin/bash\necho hi",
+			}
+
+			_, err := service.createMatadataParams("some-name", "", Networks{}, vmProps)
+			if err == nil {
+				t.Fatalf("expected an error when metadata overrides the reserved '%s' key, got nil", key)
+			}
+		})
+	}
+}
+
+func TestCreateMatadataParams_SetsStartupScriptKeys(t *testing.T) {
+	var service GoogleInstanceService
+
+	vmProps := &Properties{
+		StartupScript:    "#!/bin/bash\necho hi",
+		StartupScriptURL: "gs://bucket/startup.sh",
+	}
+
+	metadata, err := service.createMatadataParams("some-name", "", Networks{}, vmProps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	foundScript := false
+	foundScriptURL := false
+	for _, item := range metadata.Items {
+		if item.Key == startupScriptKey && *item.Value == vmProps.StartupScript {
+			foundScript = true
+		}
+		if item.Key == startupScriptURLKey && *item.Value == vmProps.StartupScriptURL {
+			foundScriptURL = true
+		}
+	}
+
+	if !foundScript {
+		t.Errorf("expected metadata to contain %q", startupScriptKey)
+	}
+	if !foundScriptURL {
+		t.Errorf("expected metadata to contain %q", startupScriptURLKey)
+	}
+}
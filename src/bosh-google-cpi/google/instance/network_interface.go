@@ -0,0 +1,22 @@
+package instance
+
+// NetworkInterface describes a single network interface to attach to an instance. It
+// is populated from the `network_interfaces` array in a VM's cloud_properties, which is
+// an alternative to the legacy single-network layout and allows a VM to be created with
+// more than one NIC.
+type NetworkInterface struct {
+	Network       string
+	ProjectID     string
+	Subnetwork    string
+	NetworkIP     string
+	ExternalIP    string
+	AliasIPRanges []AliasIPRange
+}
+
+// AliasIPRange is a secondary CIDR range that is routable to a NetworkInterface,
+// optionally bound to a named secondary range already configured on the interface's
+// subnetwork.
+type AliasIPRange struct {
+	IPCIDRRange         string
+	SubnetworkRangeName string
+}
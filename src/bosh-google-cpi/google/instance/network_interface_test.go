@@ -0,0 +1,19 @@
+package instance
+
+import (
+	"testing"
+)
+
+func TestCreateMultiNetworkInterfacesParams_RejectsTooManyInterfaces(t *testing.T) {
+	var service GoogleInstanceService
+
+	ifaces := make([]NetworkInterface, maxNetworkInterfaces+1)
+	for i := range ifaces {
+		ifaces[i] = NetworkInterface{Network: "default"}
+	}
+
+	_, err := service.createMultiNetworkInterfacesParams(ifaces, Networks{}, "us-central1-a")
+	if err == nil {
+		t.Fatalf("expected an error when exceeding %d network interfaces, got nil", maxNetworkInterfaces)
+	}
+}
@@ -0,0 +1,70 @@
+package instance
+
+// Properties represents the `cloud_properties` that can be set on a VM resource pool /
+// instance group in a BOSH deployment manifest when using this CPI.
+type Properties struct {
+	Name              string
+	Zone              string
+	MachineType       string
+	RootDiskSizeGb    int
+	RootDiskType      string
+	Stemcell          string
+	EphemeralDiskType string
+	Accelerators      []Accelerator
+	Labels            map[string]string
+	Tags              Tags
+	AutomaticRestart  bool
+	OnHostMaintenance string
+	Preemptible       bool
+	NodeGroup         string
+
+	// ProvisioningModel, InstanceTerminationAction and MaxRunDurationSeconds configure
+	// Spot VM provisioning. ProvisioningModel is either "" (standard) or "SPOT"; setting
+	// Preemptible alone is still honored for backward compatibility and is translated to
+	// the Spot equivalent.
+	ProvisioningModel         string
+	InstanceTerminationAction string
+	MaxRunDurationSeconds     int64
+
+	ServiceAccount string
+	ServiceScopes  ServiceScopes
+	TargetPool     string
+	BackendService BackendService
+
+	// NetworkInterfaces lists the network interfaces (up to GCE's 8 NIC limit) to
+	// attach to the instance, each with its own Network/Subnetwork/NetworkIP and
+	// optional AliasIPRanges. It is an alternative to the legacy single-network layout
+	// derived from Networks; when set, it replaces that single interface.
+	NetworkInterfaces []NetworkInterface
+
+	// ShieldedVM and ConfidentialVM configure GCE's Shielded VM platform protections
+	// and AMD SEV-based Confidential Computing, respectively.
+	ShieldedVM     ShieldedVM
+	ConfidentialVM ConfidentialVM
+
+	// DiskEncryption configures a customer-managed (or customer-supplied) encryption
+	// key applied to this VM's boot and ephemeral (local-SSD) disks, overriding the
+	// CPI-wide default configured on GoogleInstanceService, if any. Persistent disks
+	// attached separately via the disk service are out of scope for this field.
+	DiskEncryption DiskEncryption
+
+	// SourceInstanceTemplate is the self link or name of an existing instance template to
+	// create this VM from. When set, only the fields that must differ per-VM (name,
+	// metadata, network interfaces) are overridden; everything else is inherited from the
+	// template.
+	SourceInstanceTemplate string
+
+	// InstanceGroupManager, when set, provisions this VM as a stateful instance of an
+	// existing Managed Instance Group rather than as a standalone instance.
+	InstanceGroupManager InstanceGroupManager
+
+	// Metadata lists additional custom metadata key/value pairs to set on the instance,
+	// alongside the CPI's own user_data entry. The userDataKey is reserved and cannot be
+	// overridden here.
+	Metadata map[string]string
+
+	// StartupScript and StartupScriptURL populate GCE's "startup-script" and
+	// "startup-script-url" metadata keys, respectively.
+	StartupScript    string
+	StartupScriptURL string
+}
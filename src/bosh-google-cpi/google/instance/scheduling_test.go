@@ -0,0 +1,43 @@
+package instance
+
+import (
+	"testing"
+)
+
+func TestCreateSchedulingParams_SpotRejectsAutomaticRestart(t *testing.T) {
+	var service GoogleInstanceService
+
+	_, err := service.createSchedulingParams(true, "", false, "", provisioningModelSpot, "", 0)
+	if err == nil {
+		t.Fatalf("expected an error when combining automatic_restart with a Spot VM, got nil")
+	}
+}
+
+func TestCreateSchedulingParams_PreemptibleTranslatesToSpot(t *testing.T) {
+	var service GoogleInstanceService
+
+	scheduling, err := service.createSchedulingParams(false, "", true, "", "", "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if scheduling.ProvisioningModel != provisioningModelSpot {
+		t.Fatalf("expected preemptible=true with no provisioning_model to translate to Spot, got %q", scheduling.ProvisioningModel)
+	}
+	if scheduling.InstanceTerminationAction != "DELETE" {
+		t.Fatalf("expected default instance_termination_action of DELETE, got %q", scheduling.InstanceTerminationAction)
+	}
+}
+
+func TestCreateSchedulingParams_MaxRunDurationSetOnSpotVM(t *testing.T) {
+	var service GoogleInstanceService
+
+	scheduling, err := service.createSchedulingParams(false, "", false, "", provisioningModelSpot, "STOP", 3600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if scheduling.MaxRunDuration == nil || scheduling.MaxRunDuration.Seconds != 3600 {
+		t.Fatalf("expected MaxRunDuration of 3600 seconds, got %v", scheduling.MaxRunDuration)
+	}
+}
@@ -0,0 +1,18 @@
+package instance
+
+// ShieldedVM configures GCE's Shielded VM platform protections for an instance. Any
+// fields left unset are false, matching compute.ShieldedInstanceConfig's own zero
+// values.
+type ShieldedVM struct {
+	EnableSecureBoot          bool
+	EnableVtpm                bool
+	EnableIntegrityMonitoring bool
+}
+
+// ConfidentialVM configures AMD SEV-based Confidential Computing for an instance.
+// ConfidentialInstanceType selects the underlying technology (e.g. "SEV" or
+// "SEV_SNP") and is only meaningful when Enabled is true.
+type ConfidentialVM struct {
+	Enabled                  bool
+	ConfidentialInstanceType string
+}
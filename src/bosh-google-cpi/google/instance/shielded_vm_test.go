@@ -0,0 +1,53 @@
+package instance
+
+import "testing"
+
+func TestConfidentialVMMachineSeries(t *testing.T) {
+	cases := []struct {
+		machineType string
+		supported   bool
+	}{
+		{"zones/us-central1-a/machineTypes/n2d-standard-4", true},
+		{"zones/us-central1-a/machineTypes/c2d-standard-4", true},
+		{"zones/us-central1-a/machineTypes/c3d-standard-4", true},
+		{"zones/us-central1-a/machineTypes/n2-standard-4", false},
+		{"zones/us-central1-a/machineTypes/n1-standard-1", false},
+		{"zones/us-central1-a/machineTypes/custom-4-5120", false},
+	}
+
+	for _, c := range cases {
+		series := parseMachineTypeSeries(c.machineType)
+		supported := confidentialVMMachineSeries[series]
+		if supported != c.supported {
+			t.Errorf("parseMachineTypeSeries(%q) = %q, confidentialVMMachineSeries[%q] = %v, want %v",
+				c.machineType, series, series, supported, c.supported)
+		}
+	}
+}
+
+func TestCreateShieldedInstanceConfigParams(t *testing.T) {
+	var service GoogleInstanceService
+
+	config := service.createShieldedInstanceConfigParams(ShieldedVM{
+		EnableSecureBoot:          true,
+		EnableVtpm:                true,
+		EnableIntegrityMonitoring: false,
+	})
+
+	if !config.EnableSecureBoot || !config.EnableVtpm || config.EnableIntegrityMonitoring {
+		t.Errorf("unexpected ShieldedInstanceConfig: %+v", config)
+	}
+}
+
+func TestCreateConfidentialInstanceConfigParams(t *testing.T) {
+	var service GoogleInstanceService
+
+	if config := service.createConfidentialInstanceConfigParams(ConfidentialVM{Enabled: false}); config != nil {
+		t.Errorf("expected nil ConfidentialInstanceConfig when disabled, got %+v", config)
+	}
+
+	config := service.createConfidentialInstanceConfigParams(ConfidentialVM{Enabled: true, ConfidentialInstanceType: "SEV_SNP"})
+	if config == nil || !config.EnableConfidentialCompute || config.ConfidentialInstanceType != "SEV_SNP" {
+		t.Errorf("unexpected ConfidentialInstanceConfig: %+v", config)
+	}
+}
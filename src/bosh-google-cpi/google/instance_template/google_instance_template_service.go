@@ -0,0 +1,95 @@
+package instance_template
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-google-cpi/google/operation"
+	"bosh-google-cpi/logger"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const googleInstanceTemplateServiceLogTag = "GoogleInstanceTemplateService"
+
+// InstanceTemplateService wraps the compute.InstanceTemplates API so that a VM can
+// optionally be created from a reusable template (see GoogleInstanceService.Create's
+// SourceInstanceTemplate support) instead of having every field specified inline.
+type InstanceTemplateService interface {
+	Insert(template *compute.InstanceTemplate) (string, error)
+	Get(name string) (*compute.InstanceTemplate, bool, error)
+	Delete(name string) error
+}
+
+type GoogleInstanceTemplateService struct {
+	project          string
+	computeService   *compute.Service
+	operationService operation.Service
+	logger           logger.Logger
+}
+
+func NewGoogleInstanceTemplateService(
+	project string,
+	computeService *compute.Service,
+	operationService operation.Service,
+	logger logger.Logger,
+) GoogleInstanceTemplateService {
+	return GoogleInstanceTemplateService{
+		project:          project,
+		computeService:   computeService,
+		operationService: operationService,
+		logger:           logger,
+	}
+}
+
+// Insert creates a new instance template and returns its self-link.
+func (s GoogleInstanceTemplateService) Insert(template *compute.InstanceTemplate) (string, error) {
+	s.logger.Debug(googleInstanceTemplateServiceLogTag, "Creating Google Instance Template with params: %v", template)
+
+	operation, err := s.computeService.InstanceTemplates.Insert(s.project, template).Do()
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Failed to create Google Instance Template '%s'", template.Name)
+	}
+
+	operation, err = s.operationService.Waiter(operation, "", "")
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Failed to create Google Instance Template '%s'", template.Name)
+	}
+
+	return operation.TargetLink, nil
+}
+
+// Get returns the named instance template, or found=false if it does not exist.
+func (s GoogleInstanceTemplateService) Get(name string) (*compute.InstanceTemplate, bool, error) {
+	template, err := s.computeService.InstanceTemplates.Get(s.project, name).Do()
+	if err != nil {
+		if errHasStatusCode(err, 404) {
+			return nil, false, nil
+		}
+		return nil, false, bosherr.WrapErrorf(err, "Failed to get Google Instance Template '%s'", name)
+	}
+
+	return template, true, nil
+}
+
+// Delete removes the named instance template.
+func (s GoogleInstanceTemplateService) Delete(name string) error {
+	operation, err := s.computeService.InstanceTemplates.Delete(s.project, name).Do()
+	if err != nil {
+		if errHasStatusCode(err, 404) {
+			return nil
+		}
+		return bosherr.WrapErrorf(err, "Failed to delete Google Instance Template '%s'", name)
+	}
+
+	if _, err := s.operationService.Waiter(operation, "", ""); err != nil {
+		return bosherr.WrapErrorf(err, "Failed to delete Google Instance Template '%s'", name)
+	}
+
+	return nil
+}
+
+func errHasStatusCode(err error, statusCode int) bool {
+	googleErr, ok := err.(*googleapi.Error)
+	return ok && googleErr.Code == statusCode
+}